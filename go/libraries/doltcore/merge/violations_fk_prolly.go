@@ -15,11 +15,14 @@
 package merge
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -58,9 +61,12 @@ func prollyParentFkConstraintViolations(
 
 	childPriIdx := durable.ProllyMapFromIndex(postChild.RowData)
 	childScndryIdx := durable.ProllyMapFromIndex(postChild.IndexData)
-	primaryKD, _ := childPriIdx.Descriptors()
+	primaryKD, primaryVD := childPriIdx.Descriptors()
+	childRowEditor := childPriIdx.Editor()
+	tblName := postChild.TableName
 
-	var foundViolation bool
+	var childRowsEdited bool
+	var tasks []fkScanTask
 
 	err = prolly.DiffMaps(ctx, preParentRowData, postParentRowData, func(ctx context.Context, diff tree.Diff) error {
 		switch diff.Type {
@@ -86,14 +92,34 @@ func prollyParentFkConstraintViolations(
 				return nil
 			}
 
-			// All equivalent parents were deleted, let's check for dangling children.
-			// We search for matching keys in the child's secondary index
-			found, err := createCVsForPartialKeyMatches(ctx, partialKey, partialDesc, artEditor, primaryKD, childPriIdx, childScndryIdx, childPriIdx.Pool(), jsonData, theirRootIsh, postChild.TableName)
-			if err != nil {
-				return err
+			action := referentialActionFor(foreignKey, diff.Type)
+			action = effectiveAction(action, postChild.Schema, foreignKey)
+
+			// for a CASCADE update, the children need to be rewritten to point at
+			// the parent's new key rather than deleted or nulled out
+			var newParentPartialKey val.Tuple
+			if action == onActionCascade && diff.Type == tree.ModifiedDiff {
+				newParentPartialKey, hadNulls = makePartialKey(partialKB, postParent.Index, postParent.Schema, val.Tuple(diff.Key), val.Tuple(diff.To), preParentRowData.Pool())
+				if hadNulls {
+					// the parent's referenced columns were nulled out by the update, so
+					// there's no new key to cascade to; fall back to a violation
+					action = onActionRestrict
+				}
 			}
 
-			foundViolation = foundViolation || found
+			// buffer the partial key rather than scanning it inline; the scan
+			// itself (a read-only prefix lookup) runs on the worker pool below
+			tasks = append(tasks, fkScanTask{
+				sortKey: partialKey,
+				scan: func(ctx context.Context) (fkScanApply, error) {
+					if action == onActionRestrict {
+						return scanForDanglingChildren(ctx, partialKey, partialDesc, primaryKD, childPriIdx, childScndryIdx, childPriIdx.Pool(), jsonData, theirRootIsh, tblName, artEditor)
+					}
+					return scanForReferentialAction(ctx, action, foreignKey, postChild.Schema, primaryVD,
+						partialKey, newParentPartialKey, partialDesc, primaryKD, childPriIdx, childScndryIdx, childPriIdx.Pool(),
+						jsonData, theirRootIsh, tblName, artEditor, childRowEditor, &childRowsEdited)
+				},
+			})
 
 		case tree.AddedDiff:
 		default:
@@ -106,6 +132,11 @@ func prollyParentFkConstraintViolations(
 		return nil, false, err
 	}
 
+	foundViolation, err := runFkScansInParallel(ctx, fkMergeParallelism(ctx), tasks)
+	if err != nil {
+		return nil, false, err
+	}
+
 	artM, err = artEditor.Flush(ctx)
 	if err != nil {
 		return nil, false, err
@@ -116,9 +147,313 @@ func prollyParentFkConstraintViolations(
 		return nil, false, err
 	}
 
+	if childRowsEdited {
+		newChildRowData, err := childRowEditor.Map(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+
+		updated, err = updated.UpdateRows(ctx, durable.IndexFromProllyMap(newChildRowData))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
 	return updated, foundViolation, nil
 }
 
+// onAction is the effective action merge takes on a dangling child row
+// discovered while diffing a parent table for foreign key violations.
+type onAction byte
+
+const (
+	onActionRestrict onAction = iota
+	onActionCascade
+	onActionSetNull
+	onActionSetDefault
+)
+
+// referentialActionFor maps a foreign key's configured ON DELETE/ON UPDATE
+// action to the onAction merge should apply for the given kind of parent
+// diff. RESTRICT, NO ACTION, and the unset default action all fall back to
+// onActionRestrict, which records a constraint violation as before.
+func referentialActionFor(foreignKey doltdb.ForeignKey, diffType tree.DiffType) onAction {
+	action := foreignKey.OnDelete
+	if diffType == tree.ModifiedDiff {
+		action = foreignKey.OnUpdate
+	}
+
+	switch strings.ToUpper(action.String()) {
+	case "CASCADE":
+		return onActionCascade
+	case "SET NULL":
+		return onActionSetNull
+	case "SET DEFAULT":
+		return onActionSetDefault
+	default:
+		return onActionRestrict
+	}
+}
+
+// effectiveAction downgrades action to onActionRestrict when childSch rules
+// out actually carrying it out: a referencing column that's part of the
+// child's primary key can't be cascaded into in place (rewriting a PK
+// requires deleting and re-inserting the row, not editing the value tuple,
+// and SET NULL/SET DEFAULT can never apply to a PK column at all), and SET
+// DEFAULT can't be honored for a referencing column that's NOT NULL or that
+// has a real (non-NULL) default, since nullReferencingColumns only ever
+// writes NULL and there's no default-value expression evaluator here to
+// compute anything else. Both cases fall back to recording a constraint
+// violation instead of performing an edit that would itself violate the
+// schema or silently write the wrong value.
+func effectiveAction(action onAction, childSch schema.Schema, foreignKey doltdb.ForeignKey) onAction {
+	if action == onActionRestrict {
+		return action
+	}
+
+	pkCols := childSch.GetPKCols()
+	nonPKCols := childSch.GetNonPKCols()
+	for _, tag := range foreignKey.TableColumns {
+		if _, ok := pkCols.TagToIdx[tag]; ok {
+			return onActionRestrict
+		}
+		if action == onActionSetDefault {
+			col, ok := nonPKCols.GetByTag(tag)
+			if !ok || !col.IsNullable() || !columnDefaultsToNull(col) {
+				return onActionRestrict
+			}
+		}
+	}
+
+	return action
+}
+
+// columnDefaultsToNull reports whether col has no default, or a default
+// expression that's the literal NULL, either of which nullReferencingColumns
+// can correctly honor by leaving the field unset. Any other default (e.g.
+// `DEFAULT 0`) would require evaluating that expression to write the right
+// value, which nullReferencingColumns does not do.
+func columnDefaultsToNull(col schema.Column) bool {
+	return col.Default == "" || strings.EqualFold(col.Default, "NULL")
+}
+
+// childMatch is a child row whose secondary index entry matched a parent's
+// partial key, resolved back to its primary key and value.
+type childMatch struct {
+	key   val.Tuple
+	value val.Tuple
+}
+
+// findChildMatches performs the read-only secondary-index prefix scan shared
+// by the dangling-child and cascading-action paths below. It does not touch
+// either editor, so it's safe to run concurrently across worker goroutines.
+func findChildMatches(
+	ctx context.Context,
+	partialKey val.Tuple,
+	partialKeyDesc val.TupleDesc,
+	primaryKD val.TupleDesc,
+	primaryIdx prolly.Map,
+	secondaryIdx prolly.Map,
+	pool pool.BuffPool,
+) ([]childMatch, error) {
+	itr, err := creation.NewPrefixItr(ctx, partialKey, partialKeyDesc, secondaryIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	kb := val.NewTupleBuilder(primaryKD)
+	var matches []childMatch
+
+	for k, _, err := itr.Next(ctx); err == nil; k, _, err = itr.Next(ctx) {
+		// convert secondary idx entry to primary row key
+		// the pks of the table are the last keys of the index
+		o := k.Count() - primaryKD.Count()
+		for i := 0; i < primaryKD.Count(); i++ {
+			kb.PutRaw(i, k.GetField(o+i))
+		}
+		primaryIdxKey := kb.Build(pool)
+
+		var value val.Tuple
+		if err := primaryIdx.Get(ctx, primaryIdxKey, func(_, v val.Tuple) error {
+			value = v
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, childMatch{key: primaryIdxKey, value: value})
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// scanForReferentialAction performs the read-only portion of a cascading FK
+// action: finding the child rows a parent delete/update affects. It returns
+// an fkScanApply that performs the edit for each match on the single writer
+// goroutine, falling back to recording a ForeignKeyViol artifact for a match
+// if building or applying its edit returns an error. Edits are not checked
+// against other changes made to the same child row during the merge.
+func scanForReferentialAction(
+	ctx context.Context,
+	action onAction,
+	foreignKey doltdb.ForeignKey,
+	childSch schema.Schema,
+	valueDesc val.TupleDesc,
+	partialKey, newParentPartialKey val.Tuple,
+	partialKeyDesc val.TupleDesc,
+	primaryKD val.TupleDesc,
+	primaryIdx prolly.Map,
+	secondaryIdx prolly.Map,
+	pool pool.BuffPool,
+	jsonData []byte,
+	theirRootIsh hash.Hash,
+	tblName string,
+	artEditor prolly.ArtifactsEditor,
+	childRowEditor prolly.MutableMap,
+	childRowsEdited *bool,
+) (fkScanApply, error) {
+	matches, err := findChildMatches(ctx, partialKey, partialKeyDesc, primaryKD, primaryIdx, secondaryIdx, pool)
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+
+	return func(ctx context.Context) (bool, error) {
+		var foundViolation bool
+		for _, m := range matches {
+			var cascadeErr error
+			switch action {
+			case onActionCascade:
+				if newParentPartialKey != nil {
+					var newValue val.Tuple
+					newValue, cascadeErr = rewriteReferencingColumns(childSch, foreignKey, valueDesc, m.value, newParentPartialKey, pool)
+					if cascadeErr == nil {
+						cascadeErr = childRowEditor.Put(ctx, m.key, newValue)
+					}
+				} else {
+					cascadeErr = childRowEditor.Delete(ctx, m.key)
+				}
+			case onActionSetNull, onActionSetDefault:
+				var newValue val.Tuple
+				newValue, cascadeErr = nullReferencingColumns(childSch, foreignKey, valueDesc, m.value, pool)
+				if cascadeErr == nil {
+					cascadeErr = childRowEditor.Put(ctx, m.key, newValue)
+				}
+			}
+
+			if cascadeErr != nil {
+				// the cascaded edit could not be built or applied to this row;
+				// fall back to recording a constraint violation for it
+				meta := prolly.ConstraintViolationMeta{VInfo: jsonData, Value: m.value}
+				if err := artEditor.ReplaceConstraintViolation(ctx, m.key, theirRootIsh, prolly.ArtifactTypeForeignKeyViol, meta); err != nil {
+					return false, handleFkMultipleViolForRowErr(err, primaryKD, tblName)
+				}
+				foundViolation = true
+				continue
+			}
+
+			*childRowsEdited = true
+		}
+
+		return foundViolation, nil
+	}, nil
+}
+
+// scanForDanglingChildren performs the read-only portion of the restrict
+// path: finding child rows left dangling by a deleted/modified parent whose
+// FK action doesn't cascade. It returns an fkScanApply that records a
+// ForeignKeyViol artifact for each match on the single writer goroutine.
+func scanForDanglingChildren(
+	ctx context.Context,
+	partialKey val.Tuple,
+	partialKeyDesc val.TupleDesc,
+	primaryKD val.TupleDesc,
+	primaryIdx prolly.Map,
+	secondaryIdx prolly.Map,
+	pool pool.BuffPool,
+	jsonData []byte,
+	theirRootIsh hash.Hash,
+	tblName string,
+	artEditor prolly.ArtifactsEditor,
+) (fkScanApply, error) {
+	matches, err := findChildMatches(ctx, partialKey, partialKeyDesc, primaryKD, primaryIdx, secondaryIdx, pool)
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+
+	return func(ctx context.Context) (bool, error) {
+		for _, m := range matches {
+			meta := prolly.ConstraintViolationMeta{VInfo: jsonData, Value: m.value}
+			if err := artEditor.ReplaceConstraintViolation(ctx, m.key, theirRootIsh, prolly.ArtifactTypeForeignKeyViol, meta); err != nil {
+				return false, handleFkMultipleViolForRowErr(err, primaryKD, tblName)
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+// rewriteReferencingColumns returns a copy of a child row's value tuple with
+// the foreign key's referencing columns rewritten to newParentPartialKey,
+// used to cascade a parent update down to a matching child row.
+func rewriteReferencingColumns(childSch schema.Schema, foreignKey doltdb.ForeignKey, vd val.TupleDesc, value, newParentPartialKey val.Tuple, pool pool.BuffPool) (val.Tuple, error) {
+	vb := val.NewTupleBuilder(vd)
+	for i := 0; i < vd.Count(); i++ {
+		vb.PutRaw(i, value.GetField(i))
+	}
+
+	offset := 0
+	if schema.IsKeyless(childSch) {
+		offset = 1
+	}
+
+	for i, tag := range foreignKey.TableColumns {
+		j, ok := childSch.GetNonPKCols().TagToIdx[tag]
+		if !ok {
+			return nil, fmt.Errorf("foreign key column with tag %d not found in table '%s'", tag, foreignKey.Name)
+		}
+		vb.PutRaw(j+offset, newParentPartialKey.GetField(i))
+	}
+
+	return vb.Build(pool), nil
+}
+
+// nullReferencingColumns returns a copy of a child row's value tuple with the
+// foreign key's referencing columns left unset, which the tuple codec encodes
+// as NULL. This is used for both SET NULL and, absent a default-value
+// expression evaluator, SET DEFAULT; effectiveAction only lets a SET DEFAULT
+// action reach here when every referencing column is nullable and its
+// default is itself NULL (or unset), so nulling out is the column's actual
+// default rather than a stand-in for one — a column with a real non-NULL
+// default is routed to onActionRestrict instead.
+func nullReferencingColumns(childSch schema.Schema, foreignKey doltdb.ForeignKey, vd val.TupleDesc, value val.Tuple, pool pool.BuffPool) (val.Tuple, error) {
+	vb := val.NewTupleBuilder(vd)
+
+	offset := 0
+	if schema.IsKeyless(childSch) {
+		offset = 1
+	}
+
+	nulled := make(map[int]bool, len(foreignKey.TableColumns))
+	for _, tag := range foreignKey.TableColumns {
+		j, ok := childSch.GetNonPKCols().TagToIdx[tag]
+		if !ok {
+			return nil, fmt.Errorf("foreign key column with tag %d not found in table '%s'", tag, foreignKey.Name)
+		}
+		nulled[j+offset] = true
+	}
+
+	for i := 0; i < vd.Count(); i++ {
+		if nulled[i] {
+			continue
+		}
+		vb.PutRaw(i, value.GetField(i))
+	}
+
+	return vb.Build(pool), nil
+}
+
 func prollyChildFkConstraintViolations(
 	ctx context.Context,
 	foreignKey doltdb.ForeignKey,
@@ -141,8 +476,9 @@ func prollyChildFkConstraintViolations(
 
 	parentScndryIdx := durable.ProllyMapFromIndex(postParent.IndexData)
 
-	var foundViolation bool
-	kd, vd := postChildRowData.Descriptors()
+	kd, _ := postChildRowData.Descriptors()
+	tblName := postChild.TableName
+	var tasks []fkScanTask
 
 	err = prolly.DiffMaps(ctx, preChildRowData, postChildRowData, func(ctx context.Context, diff tree.Diff) error {
 		switch diff.Type {
@@ -153,11 +489,14 @@ func prollyChildFkConstraintViolations(
 				return nil
 			}
 
-			found, err := createCVIfNoPartialKeyMatches(ctx, k, v, partialKey, kd, vd, partialDesc, parentScndryIdx, artEditor, jsonData, theirRootIsh, postChild.TableName)
-			if err != nil {
-				return err
-			}
-			foundViolation = foundViolation || found
+			// buffer the partial key rather than scanning it inline; the scan
+			// itself (a read-only prefix lookup) runs on the worker pool below
+			tasks = append(tasks, fkScanTask{
+				sortKey: k,
+				scan: func(ctx context.Context) (fkScanApply, error) {
+					return scanForMissingParent(ctx, k, v, partialKey, partialDesc, parentScndryIdx, jsonData, theirRootIsh, kd, tblName, artEditor)
+				},
+			})
 		case tree.RemovedDiff:
 		default:
 			panic("unhandled diff type")
@@ -168,6 +507,11 @@ func prollyChildFkConstraintViolations(
 		return nil, false, err
 	}
 
+	foundViolation, err := runFkScansInParallel(ctx, fkMergeParallelism(ctx), tasks)
+	if err != nil {
+		return nil, false, err
+	}
+
 	artM, err = artEditor.Flush(ctx)
 	if err != nil {
 		return nil, false, err
@@ -181,35 +525,41 @@ func prollyChildFkConstraintViolations(
 	return updated, foundViolation, nil
 }
 
-func createCVIfNoPartialKeyMatches(
+// scanForMissingParent performs the read-only prefix scan that checks
+// whether a child row added or modified by diff.To still has a matching
+// parent. It returns an fkScanApply that records a ForeignKeyViol artifact
+// on the single writer goroutine when no parent is found.
+func scanForMissingParent(
 	ctx context.Context,
 	k, v, partialKey val.Tuple,
-	kd, vd, partialKeyDesc val.TupleDesc,
+	partialKeyDesc val.TupleDesc,
 	idx prolly.Map,
-	editor prolly.ArtifactsEditor,
 	jsonData []byte,
 	theirRootIsh hash.Hash,
-	tblName string) (bool, error) {
+	kd val.TupleDesc,
+	tblName string,
+	artEditor prolly.ArtifactsEditor,
+) (fkScanApply, error) {
 	itr, err := creation.NewPrefixItr(ctx, partialKey, partialKeyDesc, idx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	_, _, err = itr.Next(ctx)
 	if err != nil && err != io.EOF {
-		return false, err
+		return nil, err
 	}
 	if err == nil {
-		return false, nil
+		// a matching parent exists, nothing to do
+		return nil, nil
 	}
 
-	meta := prolly.ConstraintViolationMeta{VInfo: jsonData, Value: v}
-
-	err = editor.ReplaceConstraintViolation(ctx, k, theirRootIsh, prolly.ArtifactTypeForeignKeyViol, meta)
-	if err != nil {
-		return false, handleFkMultipleViolForRowErr(err, kd, tblName)
-	}
-
-	return true, nil
+	return func(ctx context.Context) (bool, error) {
+		meta := prolly.ConstraintViolationMeta{VInfo: jsonData, Value: v}
+		if err := artEditor.ReplaceConstraintViolation(ctx, k, theirRootIsh, prolly.ArtifactTypeForeignKeyViol, meta); err != nil {
+			return false, handleFkMultipleViolForRowErr(err, kd, tblName)
+		}
+		return true, nil
+	}, nil
 }
 
 func handleFkMultipleViolForRowErr(err error, kd val.TupleDesc, tblName string) error {
@@ -234,60 +584,165 @@ func getRefTblAndCols(m FkCVMeta) string {
 	return fmt.Sprintf("%s (%s)", m.ReferencedTable, strings.Join(m.ReferencedColumns, ", "))
 }
 
-func createCVsForPartialKeyMatches(
-	ctx context.Context,
-	partialKey val.Tuple,
-	partialKeyDesc val.TupleDesc,
-	editor prolly.ArtifactsEditor,
-	primaryKD val.TupleDesc,
-	primaryIdx prolly.Map,
-	secondaryIdx prolly.Map,
-	pool pool.BuffPool,
-	jsonData []byte,
-	theirRootIsh hash.Hash,
-	tblName string,
-) (bool, error) {
-	createdViolation := false
+// defaultFkMergeParallelism is used when a session doesn't set
+// dolt_merge_fk_parallelism, or ctx doesn't carry a *sql.Context at all (for
+// example in tests that diff tables directly).
+const defaultFkMergeParallelism = 4
+
+// dolt_merge_fk_parallelism is registered as a session-scoped, dynamic system
+// variable so it can be read back by fkMergeParallelism below; without this
+// registration GetSessionVariable would fail with "unknown system variable"
+// for every session and the variable would have no effect.
+func init() {
+	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{
+		{
+			Name:              "dolt_merge_fk_parallelism",
+			Scope:             sql.SystemVariableScope_Session,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemIntType("dolt_merge_fk_parallelism", 1, 1024, false),
+			Default:           int64(defaultFkMergeParallelism),
+		},
+	})
+}
 
-	itr, err := creation.NewPrefixItr(ctx, partialKey, partialKeyDesc, secondaryIdx)
+// fkMergeParallelism returns the configured worker pool size for FK
+// violation scans during merge, read from the `dolt_merge_fk_parallelism`
+// session variable.
+func fkMergeParallelism(ctx context.Context) int {
+	sqlCtx, ok := ctx.(*sql.Context)
+	if !ok {
+		return defaultFkMergeParallelism
+	}
+
+	v, err := sqlCtx.GetSessionVariable(sqlCtx, "dolt_merge_fk_parallelism")
 	if err != nil {
-		return false, err
+		return defaultFkMergeParallelism
 	}
 
-	kb := val.NewTupleBuilder(primaryKD)
+	var n int64
+	switch t := v.(type) {
+	case int64:
+		n = t
+	case int8:
+		n = int64(t)
+	default:
+		return defaultFkMergeParallelism
+	}
+	if n <= 0 {
+		return defaultFkMergeParallelism
+	}
 
-	for k, _, err := itr.Next(ctx); err == nil; k, _, err = itr.Next(ctx) {
-		createdViolation = true
+	return int(n)
+}
 
-		// convert secondary idx entry to primary row key
-		// the pks of the table are the last keys of the index
-		o := k.Count() - primaryKD.Count()
-		for i := 0; i < primaryKD.Count(); i++ {
-			j := o + i
-			kb.PutRaw(i, k.GetField(j))
-		}
-		primaryIdxKey := kb.Build(pool)
+// fkScanApply applies the result of one fkScanTask's scan step. It's only
+// ever invoked by the single writer goroutine in runFkScansInParallel, so
+// it's free to mutate an ArtifactsEditor or MutableMap that a task's scan
+// step merely read from.
+type fkScanApply func(ctx context.Context) (foundViolation bool, err error)
+
+// fkScanTask is one buffered unit of FK-violation work produced while
+// diffing a parent or child table: a deferred read-only prefix scan against
+// the sibling table's index data, plus a sortKey used to make the order
+// results are applied in independent of which worker finishes first.
+type fkScanTask struct {
+	sortKey val.Tuple
+	scan    func(ctx context.Context) (fkScanApply, error)
+}
 
-		var value val.Tuple
-		err := primaryIdx.Get(ctx, primaryIdxKey, func(k, v val.Tuple) error {
-			value = v
-			return nil
-		})
-		if err != nil {
-			return false, err
-		}
-		meta := prolly.ConstraintViolationMeta{VInfo: jsonData, Value: value}
+// runFkScansInParallel dispatches tasks across a bounded pool of parallelism
+// worker goroutines. Each task's scan step (a read-only prolly prefix range
+// lookup) runs concurrently; the fkScanApply it returns is funneled back
+// through a single writer goroutine so the caller's ArtifactsEditor (and, for
+// cascading FK actions, child row MutableMap) is only ever touched serially.
+// Results are sorted by sortKey before being applied so artifact ordering
+// stays deterministic regardless of task completion order. The first error
+// from any worker cancels the derived context and is returned.
+func runFkScansInParallel(ctx context.Context, parallelism int, tasks []fkScanTask) (bool, error) {
+	if len(tasks) == 0 {
+		return false, nil
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
 
-		err = editor.ReplaceConstraintViolation(ctx, primaryIdxKey, theirRootIsh, prolly.ArtifactTypeForeignKeyViol, meta)
-		if err != nil {
-			return false, handleFkMultipleViolForRowErr(err, primaryKD, tblName)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type scanned struct {
+		idx   int
+		apply fkScanApply
+	}
+
+	taskIdxCh := make(chan int)
+	resultCh := make(chan scanned, len(tasks))
+	errCh := make(chan error, 1)
+
+	var workers sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for idx := range taskIdxCh {
+				apply, err := tasks[idx].scan(ctx)
+				if err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+					cancel()
+					return
+				}
+				resultCh <- scanned{idx: idx, apply: apply}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(taskIdxCh)
+		for i := range tasks {
+			select {
+			case taskIdxCh <- i:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(resultCh)
+	}()
+
+	results := make([]scanned, 0, len(tasks))
+	for r := range resultCh {
+		results = append(results, r)
 	}
-	if err != nil && err != io.EOF {
+
+	select {
+	case err := <-errCh:
 		return false, err
+	default:
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return bytes.Compare(tasks[results[i].idx].sortKey, tasks[results[j].idx].sortKey) < 0
+	})
+
+	var foundViolation bool
+	for _, r := range results {
+		if r.apply == nil {
+			continue
+		}
+		found, err := r.apply(ctx)
+		if err != nil {
+			return foundViolation, err
+		}
+		foundViolation = foundViolation || found
 	}
 
-	return createdViolation, nil
+	return foundViolation, nil
 }
 
 func makePartialDescriptor(desc val.TupleDesc, n int) val.TupleDesc {
@@ -318,10 +773,6 @@ func makePartialKey(kb *val.TupleBuilder, idxSch schema.Index, tblSch schema.Sch
 	return kb.Build(pool), false
 }
 
-// TODO: Change json.NomsJson string marshalling to match json.Marshall
-// Currently it returns additional whitespace. Another option is to implement a
-// custom json encoder that matches json.NomsJson string marshalling.
-
 type FkCVMeta struct {
 	Columns           []string `json:"Columns"`
 	ForeignKey        string   `json:"ForeignKey"`
@@ -338,9 +789,17 @@ func (m FkCVMeta) Unmarshall(ctx *sql.Context) (val sql.JSONDocument, err error)
 	return sql.JSONDocument{Val: m}, nil
 }
 
+// Compare orders two FkCVMeta values by their canonical PrettyPrint string
+// rather than comparing the underlying Go structs, so that ordering is
+// stable across platforms and across old/new rows stored in the artifacts
+// index regardless of how the other value was constructed.
 func (m FkCVMeta) Compare(ctx *sql.Context, v sql.JSONValue) (cmp int, err error) {
-	ours := sql.JSONDocument{Val: m}
-	return ours.Compare(ctx, v)
+	ours := m.PrettyPrint()
+	theirs, err := v.ToString(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return strings.Compare(ours, theirs), nil
 }
 
 func (m FkCVMeta) ToString(ctx *sql.Context) (string, error) {
@@ -349,27 +808,56 @@ func (m FkCVMeta) ToString(ctx *sql.Context) (string, error) {
 
 var _ sql.JSONValue = FkCVMeta{}
 
-// PrettyPrint is a custom pretty print function to match the old format's
-// output which includes additional whitespace between keys, values, and array elements.
+// PrettyPrint renders m as JSON, matching the exact whitespace shape the old
+// NomsJson format used: a space after every ':' and ',', and a space just
+// inside each non-empty array's brackets (an empty array is written as `[]`).
+// Field values are encoded with encoding/json
+// so that a column, table, or FK name containing a quote or backslash
+// round-trips as valid JSON instead of corrupting the surrounding string, as
+// the previous fmt.Sprintf-based implementation did.
 func (m FkCVMeta) PrettyPrint() string {
-	jsonStr := fmt.Sprintf(`{`+
-		`"Columns": ["%s"], `+
-		`"ForeignKey": "%s", `+
-		`"Index": "%s", `+
-		`"OnDelete": "%s", `+
-		`"OnUpdate": "%s", `+
-		`"ReferencedColumns": ["%s"], `+
-		`"ReferencedIndex": "%s", `+
-		`"ReferencedTable": "%s", `+
-		`"Table": "%s"}`,
-		strings.Join(m.Columns, `', '`),
-		m.ForeignKey,
-		m.Index,
-		m.OnDelete,
-		m.OnUpdate,
-		strings.Join(m.ReferencedColumns, `', '`),
-		m.ReferencedIndex,
-		m.ReferencedTable,
-		m.Table)
-	return jsonStr
+	var sb strings.Builder
+	sb.WriteByte('{')
+	sb.WriteString(jsonKeyValue("Columns", m.Columns))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("ForeignKey", m.ForeignKey))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("Index", m.Index))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("OnDelete", m.OnDelete))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("OnUpdate", m.OnUpdate))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("ReferencedColumns", m.ReferencedColumns))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("ReferencedIndex", m.ReferencedIndex))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("ReferencedTable", m.ReferencedTable))
+	sb.WriteString(", ")
+	sb.WriteString(jsonKeyValue("Table", m.Table))
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// jsonKeyValue renders a single `"key": value` pair. key and value are
+// encoded with encoding/json so special characters are escaped correctly;
+// a []string value is then re-spaced to match the legacy
+// `[ "a", "b" ]` array format instead of encoding/json's compact `["a","b"]`.
+func jsonKeyValue(key string, value interface{}) string {
+	keyJSON, _ := json.Marshal(key)
+
+	if strs, ok := value.([]string); ok {
+		if len(strs) == 0 {
+			return fmt.Sprintf("%s: []", keyJSON)
+		}
+		elems := make([]string, len(strs))
+		for i, s := range strs {
+			b, _ := json.Marshal(s)
+			elems[i] = string(b)
+		}
+		return fmt.Sprintf("%s: [ %s ]", keyJSON, strings.Join(elems, ", "))
+	}
+
+	valJSON, _ := json.Marshal(value)
+	return fmt.Sprintf("%s: %s", keyJSON, valJSON)
 }
@@ -0,0 +1,254 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+func TestFkCVMetaPrettyPrintEscapesSpecialCharacters(t *testing.T) {
+	meta := FkCVMeta{
+		Columns:           []string{`a"b`, `c\d`},
+		ForeignKey:        `fk_"quoted"`,
+		Index:             "idx",
+		OnDelete:          "CASCADE",
+		OnUpdate:          "RESTRICT",
+		ReferencedColumns: []string{},
+		ReferencedIndex:   "parent_idx",
+		ReferencedTable:   `parent\table`,
+		Table:             "child",
+	}
+
+	out := meta.PrettyPrint()
+
+	// the whole thing must be valid JSON despite embedded quotes/backslashes
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+	assert.Equal(t, `fk_"quoted"`, decoded["ForeignKey"])
+	assert.Equal(t, `parent\table`, decoded["ReferencedTable"])
+
+	// empty string slices render as the legacy `[]`, not `null` or `[ ]`
+	assert.Contains(t, out, `"ReferencedColumns": []`)
+}
+
+func TestReferentialActionForDispatch(t *testing.T) {
+	fk := doltdb.ForeignKey{
+		OnDelete: doltdb.ForeignKeyReferentialAction_Cascade,
+		OnUpdate: doltdb.ForeignKeyReferentialAction_SetNull,
+	}
+
+	assert.Equal(t, onActionCascade, referentialActionFor(fk, tree.RemovedDiff))
+	assert.Equal(t, onActionSetNull, referentialActionFor(fk, tree.ModifiedDiff))
+
+	fk.OnUpdate = doltdb.ForeignKeyReferentialAction_SetDefault
+	assert.Equal(t, onActionSetDefault, referentialActionFor(fk, tree.ModifiedDiff))
+
+	fk.OnDelete = doltdb.ForeignKeyReferentialAction_Restrict
+	assert.Equal(t, onActionRestrict, referentialActionFor(fk, tree.RemovedDiff))
+
+	fk.OnDelete = doltdb.ForeignKeyReferentialAction_NoAction
+	assert.Equal(t, onActionRestrict, referentialActionFor(fk, tree.RemovedDiff))
+}
+
+// TestRunFkScansInParallelAppliesInSortKeyOrder checks that the parallel
+// worker pool produces the same deterministic application order the old
+// serial scan-as-you-diff code got for free, regardless of which worker's
+// scan step happens to finish first.
+func TestRunFkScansInParallelAppliesInSortKeyOrder(t *testing.T) {
+	var applied []int
+
+	n := 20
+	tasks := make([]fkScanTask, n)
+	for i := 0; i < n; i++ {
+		rank := n - i
+		tasks[i] = fkScanTask{
+			// descending sortKeys so application order must differ from task order
+			sortKey: val.Tuple{byte(rank)},
+			scan: func(ctx context.Context) (fkScanApply, error) {
+				return func(ctx context.Context) (bool, error) {
+					applied = append(applied, rank)
+					return false, nil
+				}, nil
+			},
+		}
+	}
+
+	_, err := runFkScansInParallel(context.Background(), 4, tasks)
+	require.NoError(t, err)
+	require.Len(t, applied, n)
+	for i := 1; i < len(applied); i++ {
+		assert.LessOrEqual(t, applied[i-1], applied[i])
+	}
+}
+
+func TestRunFkScansInParallelPropagatesFirstError(t *testing.T) {
+	wantErr := fmt.Errorf("boom")
+	tasks := []fkScanTask{
+		{
+			sortKey: val.Tuple{0},
+			scan: func(ctx context.Context) (fkScanApply, error) {
+				return nil, wantErr
+			},
+		},
+		{
+			sortKey: val.Tuple{1},
+			scan: func(ctx context.Context) (fkScanApply, error) {
+				return func(ctx context.Context) (bool, error) {
+					return true, nil
+				}, nil
+			},
+		},
+	}
+
+	_, err := runFkScansInParallel(context.Background(), 2, tasks)
+	require.Error(t, err)
+}
+
+func TestRunFkScansInParallelNoTasks(t *testing.T) {
+	found, err := runFkScansInParallel(context.Background(), 4, nil)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func mustChildSchema(t *testing.T, cols ...schema.Column) schema.Schema {
+	t.Helper()
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	require.NoError(t, err)
+	return sch
+}
+
+func TestEffectiveAction(t *testing.T) {
+	const childPKTag = 0
+	const fkTag = 1
+
+	fk := doltdb.ForeignKey{Name: "fk1", TableColumns: []uint64{fkTag}}
+
+	t.Run("ordinary nullable column may cascade, null, or default", func(t *testing.T) {
+		sch := mustChildSchema(t,
+			schema.NewColumn("id", childPKTag, types.IntKind, true),
+			schema.NewColumn("parent_id", fkTag, types.IntKind, false),
+		)
+		assert.Equal(t, onActionCascade, effectiveAction(onActionCascade, sch, fk))
+		assert.Equal(t, onActionSetNull, effectiveAction(onActionSetNull, sch, fk))
+		assert.Equal(t, onActionSetDefault, effectiveAction(onActionSetDefault, sch, fk))
+	})
+
+	t.Run("PK-referencing column always restricts", func(t *testing.T) {
+		sch := mustChildSchema(t,
+			schema.NewColumn("parent_id", fkTag, types.IntKind, true),
+		)
+		assert.Equal(t, onActionRestrict, effectiveAction(onActionCascade, sch, fk))
+		assert.Equal(t, onActionRestrict, effectiveAction(onActionSetNull, sch, fk))
+		assert.Equal(t, onActionRestrict, effectiveAction(onActionSetDefault, sch, fk))
+	})
+
+	t.Run("SET DEFAULT on a NOT NULL column restricts but CASCADE is unaffected", func(t *testing.T) {
+		sch := mustChildSchema(t,
+			schema.NewColumn("id", childPKTag, types.IntKind, true),
+			schema.NewColumn("parent_id", fkTag, types.IntKind, false, schema.NotNullConstraint()),
+		)
+		assert.Equal(t, onActionRestrict, effectiveAction(onActionSetDefault, sch, fk))
+		assert.Equal(t, onActionCascade, effectiveAction(onActionCascade, sch, fk))
+	})
+
+	t.Run("SET DEFAULT with a real non-NULL default restricts", func(t *testing.T) {
+		parentIDCol := schema.NewColumn("parent_id", fkTag, types.IntKind, false)
+		parentIDCol.Default = "0"
+		sch := mustChildSchema(t,
+			schema.NewColumn("id", childPKTag, types.IntKind, true),
+			parentIDCol,
+		)
+		assert.Equal(t, onActionRestrict, effectiveAction(onActionSetDefault, sch, fk))
+	})
+}
+
+func childValueDescriptor() val.TupleDesc {
+	return val.NewTupleDescriptor(
+		val.Type{Enc: val.Int32Enc, Nullable: true},
+		val.Type{Enc: val.StringEnc, Nullable: true},
+	)
+}
+
+func TestRewriteReferencingColumns(t *testing.T) {
+	sch := mustChildSchema(t,
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("parent_id", 1, types.IntKind, false),
+		schema.NewColumn("name", 2, types.StringKind, false),
+	)
+	fk := doltdb.ForeignKey{Name: "fk1", TableColumns: []uint64{1}}
+	vd := childValueDescriptor()
+	bp := pool.NewBuffPool()
+
+	vb := val.NewTupleBuilder(vd)
+	vb.PutInt32(0, 7)
+	vb.PutString(1, "bob")
+	before := vb.Build(bp)
+
+	newParentKeyDesc := val.NewTupleDescriptor(val.Type{Enc: val.Int32Enc, Nullable: false})
+	nkb := val.NewTupleBuilder(newParentKeyDesc)
+	nkb.PutInt32(0, 42)
+	newParentKey := nkb.Build(bp)
+
+	after, err := rewriteReferencingColumns(sch, fk, vd, before, newParentKey, bp)
+	require.NoError(t, err)
+
+	gotFK, ok := vd.GetInt32(0, after)
+	require.True(t, ok)
+	assert.Equal(t, int32(42), gotFK)
+
+	// every other column is carried over unchanged
+	gotName, ok := vd.GetString(1, after)
+	require.True(t, ok)
+	assert.Equal(t, "bob", gotName)
+}
+
+func TestNullReferencingColumns(t *testing.T) {
+	sch := mustChildSchema(t,
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("parent_id", 1, types.IntKind, false),
+		schema.NewColumn("name", 2, types.StringKind, false),
+	)
+	fk := doltdb.ForeignKey{Name: "fk1", TableColumns: []uint64{1}}
+	vd := childValueDescriptor()
+	bp := pool.NewBuffPool()
+
+	vb := val.NewTupleBuilder(vd)
+	vb.PutInt32(0, 7)
+	vb.PutString(1, "bob")
+	before := vb.Build(bp)
+
+	after, err := nullReferencingColumns(sch, fk, vd, before, bp)
+	require.NoError(t, err)
+
+	assert.True(t, after.FieldIsNull(0))
+
+	// non-FK columns are carried over unchanged
+	gotName, ok := vd.GetString(1, after)
+	require.True(t, ok)
+	assert.Equal(t, "bob", gotName)
+}
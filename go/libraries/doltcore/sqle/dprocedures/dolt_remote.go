@@ -30,33 +30,29 @@ import (
 
 // doltRemote is the stored procedure version of the CLI `dolt remote` command
 func doltRemote(ctx *sql.Context, args ...string) (sql.RowIter, error) {
-	res, err := doDoltRemote(ctx, args)
-	if err != nil {
-		return nil, err
-	}
-	return rowToIter(res), nil
+	return doDoltRemote(ctx, args)
 }
 
-// doDoltRemote is used as sql dolt_remote command for only creating or deleting remotes, not listing.
+// doDoltRemote is used as sql dolt_remote command for managing remotes, not listing.
 // To list remotes, dolt_remotes system table is used.
-func doDoltRemote(ctx *sql.Context, args []string) (int, error) {
+func doDoltRemote(ctx *sql.Context, args []string) (sql.RowIter, error) {
 	dbName := ctx.GetCurrentDatabase()
 	if len(dbName) == 0 {
-		return 1, fmt.Errorf("Empty database name.")
+		return nil, fmt.Errorf("Empty database name.")
 	}
 	dSess := dsess.DSessFromSess(ctx.Session)
 	dbData, ok := dSess.GetDbData(ctx, dbName)
 	if !ok {
-		return 1, fmt.Errorf("Could not load database %s", dbName)
+		return nil, fmt.Errorf("Could not load database %s", dbName)
 	}
 
 	apr, err := cli.CreateRemoteArgParser().Parse(args)
 	if err != nil {
-		return 1, err
+		return nil, err
 	}
 
 	if apr.NArg() == 0 {
-		return 1, fmt.Errorf("error: invalid argument, use 'dolt_remotes' system table to list remotes")
+		return nil, fmt.Errorf("error: invalid argument, use 'dolt_remotes' system table to list remotes")
 	}
 
 	switch apr.Arg(0) {
@@ -64,14 +60,22 @@ func doDoltRemote(ctx *sql.Context, args []string) (int, error) {
 		err = addRemote(apr, dSess)
 	case "remove", "rm":
 		err = removeRemote(ctx, dbData, apr, dSess)
+	case "rename":
+		err = renameRemote(ctx, dbData, apr, dSess)
+	case "set-url":
+		err = setRemoteUrl(apr, dSess)
+	case "get-url":
+		return getRemoteUrl(apr, dSess)
+	case "show":
+		return showRemote(ctx, dbData, apr, dSess)
 	default:
 		err = fmt.Errorf("error: invalid argument")
 	}
 
 	if err != nil {
-		return 1, err
+		return nil, err
 	}
-	return 0, nil
+	return rowToIter(0), nil
 }
 
 func addRemote(apr *argparser.ArgParseResults, sess *dsess.DoltSession) error {
@@ -150,4 +154,175 @@ func removeRemote(ctx *sql.Context, dbd env.DbData, apr *argparser.ArgParseResul
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ensureRemoteNameAvailable returns an error if repoState already has a
+// remote named name, so a rename can't silently clobber it.
+func ensureRemoteNameAvailable(repoState *env.RepoState, name string) error {
+	if _, exists := repoState.Remotes[name]; exists {
+		return fmt.Errorf("error: remote '%s' already exists", name)
+	}
+	return nil
+}
+
+// renameRemote renames a remote, updating the repo state and rewriting any
+// `refs/remotes/<old>/...` tracking refs to live under `refs/remotes/<new>/...`.
+func renameRemote(ctx *sql.Context, dbd env.DbData, apr *argparser.ArgParseResults, sess *dsess.DoltSession) error {
+	if apr.NArg() != 3 {
+		return fmt.Errorf("error: invalid argument")
+	}
+
+	old := strings.TrimSpace(apr.Arg(1))
+	newName := strings.TrimSpace(apr.Arg(2))
+
+	fs := sess.Provider().FileSystem()
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return err
+	}
+
+	remote, ok := repoState.Remotes[old]
+	if !ok {
+		return fmt.Errorf("error: unknown remote: '%s'", old)
+	}
+
+	if err := ensureRemoteNameAvailable(repoState, newName); err != nil {
+		return err
+	}
+
+	ddb := dbd.Ddb
+	refs, err := ddb.GetRemoteRefs(ctx)
+	if err != nil {
+		return fmt.Errorf("error: failed to read from db, cause: %s", env.ErrFailedToReadFromDb.Error())
+	}
+
+	for _, r := range refs {
+		rr := r.(ref.RemoteRef)
+		if rr.GetRemote() != old {
+			continue
+		}
+
+		cm, err := ddb.ResolveCommitRef(ctx, rr)
+		if err != nil {
+			return fmt.Errorf("%w; failed to resolve remote tracking ref '%s'; %s", env.ErrFailedToDeleteRemote, rr.String(), err.Error())
+		}
+
+		newRef := ref.NewRemoteRef(newName, rr.GetBranch())
+		err = ddb.NewBranchAtCommit(ctx, newRef, cm)
+		if err != nil {
+			return fmt.Errorf("%w; failed to create remote tracking ref '%s'; %s", env.ErrFailedToDeleteRemote, newRef.String(), err.Error())
+		}
+
+		err = ddb.DeleteBranch(ctx, rr)
+		if err != nil {
+			return fmt.Errorf("%w; failed to delete remote tracking ref '%s'; %s", env.ErrFailedToDeleteRemote, rr.String(), err.Error())
+		}
+	}
+
+	remote.Name = newName
+	delete(repoState.Remotes, old)
+	repoState.Remotes[newName] = remote
+
+	return repoState.Save(fs)
+}
+
+// setRemoteUrl replaces the url for an existing remote. `dolt remote set-url`
+// also accepts a `--push` flag to update only the push url, but dolt remotes
+// only track a single url and cli.CreateRemoteArgParser does not register
+// that flag, so `--push` is not recognized here.
+func setRemoteUrl(apr *argparser.ArgParseResults, sess *dsess.DoltSession) error {
+	if apr.NArg() != 3 {
+		return fmt.Errorf("error: invalid argument")
+	}
+
+	remoteName := strings.TrimSpace(apr.Arg(1))
+	remoteUrl := apr.Arg(2)
+
+	fs := sess.Provider().FileSystem()
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := repoState.Remotes[remoteName]; !ok {
+		return fmt.Errorf("error: unknown remote: '%s'", remoteName)
+	}
+
+	scheme, absRemoteUrl, err := env.GetAbsRemoteUrl(sess.Provider().FileSystem(), &config.MapConfig{}, remoteUrl)
+	if err != nil {
+		return err
+	}
+
+	params, err := parseRemoteArgs(apr, scheme, absRemoteUrl)
+	if err != nil {
+		return err
+	}
+
+	repoState.Remotes[remoteName] = env.NewRemote(remoteName, absRemoteUrl, params, nil)
+
+	return repoState.Save(fs)
+}
+
+// getRemoteUrl returns the url of a single remote as a one-column row.
+func getRemoteUrl(apr *argparser.ArgParseResults, sess *dsess.DoltSession) (sql.RowIter, error) {
+	if apr.NArg() != 2 {
+		return nil, fmt.Errorf("error: invalid argument")
+	}
+
+	remoteName := strings.TrimSpace(apr.Arg(1))
+
+	fs := sess.Provider().FileSystem()
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, ok := repoState.Remotes[remoteName]
+	if !ok {
+		return nil, fmt.Errorf("error: unknown remote: '%s'", remoteName)
+	}
+
+	return sql.RowsToRowIter(sql.Row{remote.Url}), nil
+}
+
+// showRemote returns a single structured row describing a remote: its url,
+// a fetch refspec derived from the remote's name, a fixed push refspec, and
+// the tracking branches currently stored for it. The fetch specs saved with
+// the remote in the repo state are not read.
+func showRemote(ctx *sql.Context, dbd env.DbData, apr *argparser.ArgParseResults, sess *dsess.DoltSession) (sql.RowIter, error) {
+	if apr.NArg() != 2 {
+		return nil, fmt.Errorf("error: invalid argument")
+	}
+
+	remoteName := strings.TrimSpace(apr.Arg(1))
+
+	fs := sess.Provider().FileSystem()
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, ok := repoState.Remotes[remoteName]
+	if !ok {
+		return nil, fmt.Errorf("error: unknown remote: '%s'", remoteName)
+	}
+
+	ddb := dbd.Ddb
+	refs, err := ddb.GetRemoteRefs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read from db, cause: %s", env.ErrFailedToReadFromDb.Error())
+	}
+
+	var tracking []string
+	for _, r := range refs {
+		rr := r.(ref.RemoteRef)
+		if rr.GetRemote() == remoteName {
+			tracking = append(tracking, rr.GetBranch())
+		}
+	}
+
+	fetchSpec := fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remoteName)
+	pushSpec := "refs/heads/*:refs/heads/*"
+
+	return sql.RowsToRowIter(sql.Row{remoteName, remote.Url, fetchSpec, pushSpec, strings.Join(tracking, ", ")}), nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+func TestEnsureRemoteNameAvailable(t *testing.T) {
+	repoState := &env.RepoState{
+		Remotes: map[string]env.Remote{
+			"backup": env.NewRemote("backup", "https://example.com/backup", nil, nil),
+		},
+	}
+
+	// renaming onto a name that's already taken must error, not clobber it
+	err := ensureRemoteNameAvailable(repoState, "backup")
+	assert.Error(t, err)
+	assert.Contains(t, repoState.Remotes, "backup")
+
+	// a fresh name is fine
+	assert.NoError(t, ensureRemoteNameAvailable(repoState, "origin"))
+}